@@ -5,11 +5,9 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"time"
 
 	"github.com/mikesmitty/sht4x"
 	"periph.io/x/conn/v3/i2c/i2creg"
-	"periph.io/x/conn/v3/physic"
 	"periph.io/x/host/v3"
 )
 
@@ -47,57 +45,29 @@ func main() {
 
 	slog.SetDefault(slog.Default().With("serial", dev.Serial))
 
-	var e physic.Env
-	err = dev.Sense(&e)
-	if err != nil {
-		fatal("sensor read failed", err, 2)
-	}
-
-	t := time.NewTimer(*dur)
-	tk := time.NewTicker(1 * time.Minute)
-	lowTemp := e.Temperature.Celsius()
-
-	slog.Info("beginning heat cycle", "duration", *dur, "temperature", e.Temperature.Celsius())
+	dc := sht4x.NewDecontaminator(dev, &sht4x.DecontaminatorOpts{
+		Duration: *dur,
+		OnEvent:  logDecontaminationEvent,
+	})
 
-	for {
-		select {
-		case <-t.C:
-			slog.Info("heat cycle completed after", "duration", *dur)
-			return
-		case <-tk.C:
-			slog.Info("status", "temperature", e.Temperature.Celsius())
-		default:
-			e, err = heatCycle(dev, lowTemp)
-			if err != nil {
-				fatal("heat cycle failed", err, 2)
-			}
-		}
+	slog.Info("beginning heat cycle", "duration", *dur)
+	if err := dc.Run(); err != nil {
+		fatal("heat cycle failed", err, 2)
 	}
+	slog.Info("heat cycle completed", "duration", *dur)
 }
 
-func heatCycle(dev *sht4x.Dev, lowTemp float64) (physic.Env, error) {
-	e, err := dev.ActivateHeater(sht4x.HeaterHighLong)
-	if err != nil {
-		return e, fmt.Errorf("heater activation failed: %w", err)
-	}
-
-	if e.Temperature.Celsius() < lowTemp {
-		return e, fmt.Errorf("temperature did not increase after activating heater")
-	}
-	if e.Temperature.Celsius() > 110 {
-		slog.Warn("temperature is above 110°C. Pausing for 10 seconds to allow sensor to cool down.", "temperature", e.Temperature.Celsius())
-		time.Sleep(10 * time.Second)
-		for {
-			err = dev.Sense(&e)
-			if err != nil {
-				return e, err
-			}
-			if e.Temperature.Celsius() < 110 {
-				break
-			}
-		}
+func logDecontaminationEvent(ev sht4x.DecontaminationEvent) {
+	switch ev.Kind {
+	case sht4x.CycleStarted:
+		slog.Info("status", "temperature", ev.Env.Temperature.Celsius(), "elapsed", ev.Elapsed)
+	case sht4x.CoolDown:
+		slog.Warn("temperature is above the cool-down ceiling, pausing to allow sensor to cool down", "temperature", ev.Env.Temperature.Celsius())
+	case sht4x.Aborted:
+		slog.Error("decontamination aborted", "error", ev.Err, "elapsed", ev.Elapsed)
+	case sht4x.Completed:
+		slog.Info("decontamination completed", "elapsed", ev.Elapsed)
 	}
-	return e, nil
 }
 
 func fatal(msg string, err error, code int) {
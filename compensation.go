@@ -0,0 +1,119 @@
+package sht4x
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// Compensation carries an absolute-humidity reading in the formats needed
+// to feed an air-quality sensor's humidity compensation input (e.g. the
+// SGP30's set_absolute_humidity command).
+type Compensation struct {
+	// Fixed8p8 is the absolute humidity in the SGP30's 8.8 fixed-point
+	// g/m³ format: the upper byte is the integer part, the lower byte is
+	// the fractional part in 1/256ths.
+	Fixed8p8 uint16
+	// AbsoluteHumidity is the same value as a raw float, in g/m³.
+	AbsoluteHumidity float64
+}
+
+// absoluteHumidityToFixed8p8 converts an absolute humidity in g/m³ to the
+// SGP30's 8.8 fixed-point format, clamping to the representable range.
+func absoluteHumidityToFixed8p8(ah float64) uint16 {
+	scaled := math.Round(ah * 256)
+	if scaled < 0 {
+		scaled = 0
+	} else if scaled > math.MaxUint16 {
+		scaled = math.MaxUint16
+	}
+	return uint16(scaled)
+}
+
+// AbsoluteHumidity8p8 takes a single reading and returns the absolute
+// humidity in the SGP30's 8.8 fixed-point g/m³ format.
+func (d *Dev) AbsoluteHumidity8p8() (uint16, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return 0, d.wrap(errors.New("already sensing continuously"))
+	}
+
+	var r Derived
+	if err := d.senseDerived(&r); err != nil {
+		return 0, err
+	}
+	return absoluteHumidityToFixed8p8(r.AbsoluteHumidity), nil
+}
+
+// CompensationStream periodically reads the sensor and emits the absolute
+// humidity, ready to feed into an air-quality sensor's humidity
+// compensation input. It shares the same continuous-sensing slot as
+// SenseContinuous and SenseContinuousDerived, so it returns an error rather
+// than silently stealing the I²C transaction out from under an already
+// running continuous stream.
+//
+// The application must call Halt() (or cancel ctx) to stop the stream and
+// close the channel.
+func (d *Dev) CompensationStream(ctx context.Context, interval time.Duration) (<-chan Compensation, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return nil, d.wrap(errors.New("already sensing continuously"))
+	}
+
+	out := make(chan Compensation)
+	d.stop = make(chan struct{})
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(out)
+		d.compensationStream(ctx, interval, out, d.stop)
+	}()
+	return out, nil
+}
+
+func (d *Dev) compensationStream(ctx context.Context, interval time.Duration, out chan<- Compensation, stop <-chan struct{}) {
+	if interval < d.measDelay {
+		interval = d.measDelay
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	d.logger.Info("compensation stream started", "interval", interval)
+	defer d.logger.Info("compensation stream stopped")
+
+	for {
+		var r Derived
+		d.mu.Lock()
+		err := d.senseDerived(&r)
+		d.mu.Unlock()
+		if err != nil {
+			d.logger.Error("compensationStream", "error", err)
+			d.reportErr(err)
+		} else {
+			c := Compensation{
+				Fixed8p8:         absoluteHumidityToFixed8p8(r.AbsoluteHumidity),
+				AbsoluteHumidity: r.AbsoluteHumidity,
+			}
+			select {
+			case out <- c:
+			case <-stop:
+				return
+			case <-ctx.Done():
+				d.reportErr(ctx.Err())
+				return
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			d.reportErr(ctx.Err())
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package sht4x
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// Setpoint describes the target condition a HeaterController drives toward.
+// If TargetDewPointMargin is non-zero it takes precedence over TargetRH.
+type Setpoint struct {
+	// TargetDewPointMargin is the minimum temperature, in °C, the sensor
+	// should be held above the ambient dew point, to prevent condensation
+	// forming on the die.
+	TargetDewPointMargin float64
+	// TargetRH is a target relative humidity, in percent, to drive the
+	// sensor toward directly instead of a dew-point margin.
+	TargetRH float64
+}
+
+// PIDGains configures a HeaterController's discrete PID loop.
+type PIDGains struct {
+	Kp, Ki, Kd float64
+	// IntegralClamp bounds the accumulated integral term to limit windup.
+	// Zero disables clamping.
+	IntegralClamp float64
+}
+
+// HeaterControllerOpts configures a HeaterController.
+type HeaterControllerOpts struct {
+	Setpoint Setpoint
+	Gains    PIDGains
+
+	// Period is how often the controller samples and re-evaluates the PID
+	// loop. Defaults to 1s.
+	Period time.Duration
+
+	// MaxAverageDutyCycle caps the average fraction of time the heater may
+	// be commanded on, independent of (and in addition to) the underlying
+	// Dev's own duty-cycle governor. Defaults to the Dev's configured
+	// Opts.DutyCycleLimit.
+	MaxAverageDutyCycle float64
+}
+
+// Status is a snapshot of a HeaterController's state, for observability.
+type Status struct {
+	Env physic.Env
+	// Err is the current control error (positive means more heat wanted).
+	Err float64
+	// Mode is the heater mode the controller is currently driving, or -1 if
+	// the heater is off this period.
+	Mode int
+	// RecentDutyCycle is the fraction of the Dev's duty-cycle window
+	// (Opts.DutyCycleWindow) the heater has been on for recently.
+	RecentDutyCycle float64
+}
+
+// HeaterController runs a discrete PID loop on its own goroutine, pulsing
+// the heater to hold a Setpoint: either a minimum dew-point margin (to
+// prevent condensation on the die) or a target relative humidity. It
+// respects the underlying Dev's heater duty-cycle governor, in addition to
+// its own MaxAverageDutyCycle cap.
+type HeaterController struct {
+	dev  *Dev
+	opts *HeaterControllerOpts
+
+	mu       sync.Mutex
+	status   Status
+	integral float64
+	prevErr  float64
+	haveErr  bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewHeaterController builds a HeaterController for dev. opts must not be
+// nil; Setpoint and Gains should be configured by the caller.
+func NewHeaterController(dev *Dev, opts *HeaterControllerOpts) *HeaterController {
+	if opts == nil {
+		opts = &HeaterControllerOpts{}
+	}
+	if opts.Period == 0 {
+		opts.Period = 1 * time.Second
+	}
+	if opts.MaxAverageDutyCycle == 0 {
+		opts.MaxAverageDutyCycle = dev.dutyCycleLimit
+	}
+	return &HeaterController{
+		dev:  dev,
+		opts: opts,
+		status: Status{
+			Mode: -1,
+		},
+	}
+}
+
+// Start begins the control loop. It returns immediately; the loop runs
+// until ctx is canceled or Stop is called.
+func (hc *HeaterController) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	hc.cancel = cancel
+	hc.wg.Add(1)
+	go func() {
+		defer hc.wg.Done()
+		hc.run(ctx)
+	}()
+}
+
+// Stop cancels the control loop and waits for it to exit.
+func (hc *HeaterController) Stop() {
+	if hc.cancel != nil {
+		hc.cancel()
+	}
+	hc.wg.Wait()
+}
+
+// Status returns a snapshot of the controller's current error, output mode,
+// and recent duty cycle.
+func (hc *HeaterController) Status() Status {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	return hc.status
+}
+
+func (hc *HeaterController) run(ctx context.Context) {
+	t := time.NewTicker(hc.opts.Period)
+	defer t.Stop()
+
+	hc.dev.logger.Info("heater controller started", "period", hc.opts.Period)
+	defer hc.dev.logger.Info("heater controller stopped")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		hc.step()
+	}
+}
+
+func (hc *HeaterController) step() {
+	var r Derived
+	if err := hc.dev.SenseDerived(&r); err != nil {
+		hc.dev.logger.Error("heater controller sense failed", "error", err)
+		return
+	}
+
+	controlErr := hc.controlError(&r)
+	output := hc.pid(controlErr)
+
+	mode := -1
+	budget := time.Duration(float64(hc.dev.dutyCycleWindow) * hc.opts.MaxAverageDutyCycle)
+	used := hc.dev.DutyCycleUsed()
+
+	if m, on := pidOutputToMode(output); on && used+heaterModeDuration(m) <= budget {
+		mode = m
+		if _, err := hc.dev.ActivateHeater(mode); err != nil {
+			hc.dev.logger.Warn("heater controller activation skipped", "error", err)
+			mode = -1
+		} else {
+			used = hc.dev.DutyCycleUsed()
+		}
+	}
+
+	hc.mu.Lock()
+	hc.status = Status{
+		Env:             r.Env,
+		Err:             controlErr,
+		Mode:            mode,
+		RecentDutyCycle: used.Seconds() / hc.dev.dutyCycleWindow.Seconds(),
+	}
+	hc.mu.Unlock()
+}
+
+// controlError computes the PID error: positive means the heater should run
+// harder. For a dew-point margin setpoint, error is the shortfall between
+// the desired and actual margin above dew point. For a target-RH setpoint,
+// error is how far actual RH is above the target (heating dries the air).
+func (hc *HeaterController) controlError(r *Derived) float64 {
+	if hc.opts.Setpoint.TargetDewPointMargin != 0 {
+		margin := r.Env.Temperature.Celsius() - r.DewPoint
+		return hc.opts.Setpoint.TargetDewPointMargin - margin
+	}
+	actualRH := float64(r.Env.Humidity) / float64(physic.PercentRH)
+	return actualRH - hc.opts.Setpoint.TargetRH
+}
+
+func (hc *HeaterController) pid(controlErr float64) float64 {
+	if math.IsNaN(controlErr) || math.IsInf(controlErr, 0) {
+		// A non-finite control error (e.g. from a degenerate sensor reading)
+		// must never reach hc.integral: once latched there, NaN poisons
+		// every future cycle's integral term even after the reading
+		// recovers. Treat this cycle as "heater off" instead.
+		return 0
+	}
+
+	dt := hc.opts.Period.Seconds()
+
+	hc.integral += controlErr * dt
+	if hc.opts.Gains.IntegralClamp > 0 {
+		if hc.integral > hc.opts.Gains.IntegralClamp {
+			hc.integral = hc.opts.Gains.IntegralClamp
+		} else if hc.integral < -hc.opts.Gains.IntegralClamp {
+			hc.integral = -hc.opts.Gains.IntegralClamp
+		}
+	}
+
+	var derivative float64
+	if hc.haveErr {
+		derivative = (controlErr - hc.prevErr) / dt
+	}
+	hc.prevErr = controlErr
+	hc.haveErr = true
+
+	output := hc.opts.Gains.Kp*controlErr + hc.opts.Gains.Ki*hc.integral + hc.opts.Gains.Kd*derivative
+	if math.IsNaN(output) || math.IsInf(output, 0) {
+		return 0
+	}
+	if output < 0 {
+		output = 0
+	} else if output > 1 {
+		output = 1
+	}
+	return output
+}
+
+// pidOutputToMode maps a normalized [0,1] PID output to a discrete heater
+// mode. Output at or below 0 keeps the heater off.
+//
+// The ladder is ordered by actual energy delivered per activation (power
+// from the datasheet's mode table times the mode's on-time), not by mode
+// name, since that's what must increase monotonically with output for the
+// PID loop to converge instead of oscillating:
+// Low=20mW*100ms=2mW·s, Medium=110mW*100ms=11mW·s,
+// LowLong=20mW*1s=20mW·s, High=200mW*100ms=20mW·s,
+// MediumLong=110mW*1s=110mW·s, HighLong=200mW*1s=200mW·s.
+func pidOutputToMode(output float64) (mode int, on bool) {
+	switch {
+	case output <= 0:
+		return 0, false
+	case output < 0.2:
+		return HeaterLow, true
+	case output < 0.4:
+		return HeaterMedium, true
+	case output < 0.6:
+		return HeaterLowLong, true
+	case output < 0.8:
+		return HeaterHigh, true
+	case output < 0.95:
+		return HeaterMediumLong, true
+	default:
+		return HeaterHighLong, true
+	}
+}
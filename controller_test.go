@@ -0,0 +1,118 @@
+package sht4x
+
+import (
+	"math"
+	"testing"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+func envAt(tempC, rhPercent float64) physic.Env {
+	var e physic.Env
+	quantizeEnv(&e, tempC, rhPercent)
+	return e
+}
+
+func TestControlErrorDewPointMargin(t *testing.T) {
+	hc := &HeaterController{opts: &HeaterControllerOpts{Setpoint: Setpoint{TargetDewPointMargin: 5}}}
+	r := &Derived{Env: envAt(20, 50), DewPoint: 16}
+
+	// margin = 20 - 16 = 4, want 5, so error = 1 (more heat wanted).
+	if err := hc.controlError(r); err != 1 {
+		t.Errorf("controlError = %v, want 1", err)
+	}
+}
+
+func TestControlErrorTargetRH(t *testing.T) {
+	hc := &HeaterController{opts: &HeaterControllerOpts{Setpoint: Setpoint{TargetRH: 40}}}
+	r := &Derived{Env: envAt(20, 55)}
+
+	if err := hc.controlError(r); err < 14.999 || err > 15.001 {
+		t.Errorf("controlError = %v, want ~15", err)
+	}
+}
+
+func TestPIDOutputClamped(t *testing.T) {
+	hc := &HeaterController{opts: &HeaterControllerOpts{Gains: PIDGains{Kp: 100}}}
+	if out := hc.pid(10); out != 1 {
+		t.Errorf("pid(10) = %v, want 1 (clamped)", out)
+	}
+
+	hc = &HeaterController{opts: &HeaterControllerOpts{Gains: PIDGains{Kp: 100}}}
+	if out := hc.pid(-10); out != 0 {
+		t.Errorf("pid(-10) = %v, want 0 (clamped)", out)
+	}
+}
+
+// TestPIDRejectsNonFiniteError covers a degenerate sensor reading (e.g. the
+// dry-air NaN that used to leak out of computeDerived) reaching pid(): the
+// output for that cycle must come back "off" and the integral must not
+// latch the NaN, so a later, valid reading isn't poisoned forever.
+func TestPIDRejectsNonFiniteError(t *testing.T) {
+	hc := &HeaterController{opts: &HeaterControllerOpts{Period: 1, Gains: PIDGains{Kp: 1, Ki: 1}}}
+
+	if out := hc.pid(math.NaN()); out != 0 {
+		t.Errorf("pid(NaN) = %v, want 0", out)
+	}
+	if out := hc.pid(math.Inf(1)); out != 0 {
+		t.Errorf("pid(+Inf) = %v, want 0", out)
+	}
+	if math.IsNaN(hc.integral) || math.IsInf(hc.integral, 0) {
+		t.Fatalf("integral = %v after non-finite input, want it left untouched", hc.integral)
+	}
+
+	// A subsequent valid error must produce a normal, finite output.
+	if out := hc.pid(0.5); math.IsNaN(out) || math.IsInf(out, 0) {
+		t.Errorf("pid(0.5) after non-finite input = %v, want finite", out)
+	}
+}
+
+func TestPidOutputToMode(t *testing.T) {
+	tests := []struct {
+		output   float64
+		wantMode int
+		wantOn   bool
+	}{
+		{0, 0, false},
+		{-1, 0, false},
+		{0.1, HeaterLow, true},
+		{0.3, HeaterMedium, true},
+		{0.5, HeaterLowLong, true},
+		{0.7, HeaterHigh, true},
+		{0.9, HeaterMediumLong, true},
+		{1, HeaterHighLong, true},
+	}
+	for _, tt := range tests {
+		mode, on := pidOutputToMode(tt.output)
+		if mode != tt.wantMode || on != tt.wantOn {
+			t.Errorf("pidOutputToMode(%v) = (%v, %v), want (%v, %v)", tt.output, mode, on, tt.wantMode, tt.wantOn)
+		}
+	}
+}
+
+// TestPidOutputToModeMonotonicEnergy guards the fix itself: as output
+// climbs, the delivered energy (power × on-time) of the selected mode must
+// never decrease, or the PID loop can oscillate instead of converging.
+func TestPidOutputToModeMonotonicEnergy(t *testing.T) {
+	energyMilliWattSec := map[int]float64{
+		HeaterLow:        2,
+		HeaterMedium:     11,
+		HeaterLowLong:    20,
+		HeaterHigh:       20,
+		HeaterMediumLong: 110,
+		HeaterHighLong:   200,
+	}
+
+	prev := -1.0
+	for output := 0.01; output < 1.0; output += 0.01 {
+		mode, on := pidOutputToMode(output)
+		if !on {
+			continue
+		}
+		energy := energyMilliWattSec[mode]
+		if energy < prev {
+			t.Fatalf("energy at output=%.2f (mode %d) = %v, decreased from %v", output, mode, energy, prev)
+		}
+		prev = energy
+	}
+}
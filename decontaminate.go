@@ -0,0 +1,135 @@
+package sht4x
+
+import (
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// DecontaminationEventKind identifies the kind of event emitted during a
+// Decontaminator run.
+type DecontaminationEventKind int
+
+const (
+	CycleStarted DecontaminationEventKind = iota
+	CoolDown
+	Completed
+	Aborted
+)
+
+func (k DecontaminationEventKind) String() string {
+	switch k {
+	case CycleStarted:
+		return "cycle started"
+	case CoolDown:
+		return "cool down"
+	case Completed:
+		return "completed"
+	case Aborted:
+		return "aborted"
+	default:
+		return "unknown"
+	}
+}
+
+// DecontaminationEvent is passed to DecontaminatorOpts.OnEvent as a
+// Decontaminator run progresses.
+type DecontaminationEvent struct {
+	Kind    DecontaminationEventKind
+	Env     physic.Env
+	Elapsed time.Duration
+	// Err is set when Kind is Aborted.
+	Err error
+}
+
+type DecontaminatorOpts struct {
+	// Duration is the total heater on-time to run across all bursts.
+	Duration time.Duration
+	// CoolDownCeiling is the temperature, in °C, above which the heater is
+	// paused to let the sensor cool. Defaults to 110.
+	CoolDownCeiling float64
+	// CoolDownPeriod is how long to wait between cool-down temperature
+	// checks. Defaults to 10s.
+	CoolDownPeriod time.Duration
+	// OnEvent, if set, is called synchronously for each DecontaminationEvent.
+	OnEvent func(DecontaminationEvent)
+}
+
+func DefaultDecontaminatorOpts() *DecontaminatorOpts {
+	return &DecontaminatorOpts{
+		CoolDownCeiling: 110,
+		CoolDownPeriod:  10 * time.Second,
+	}
+}
+
+// Decontaminator runs the sensor's heater at HeaterHighLong for an extended
+// period to offgas VOC contaminants, per
+// https://web.archive.org/web/20221006045126/https://sensirion.com/media/documents/FEE9F039/62459F54/Application_Note_Heater_Decontamination_SHT4xX.pdf
+//
+// It overrides the heater's duty-cycle governor for the duration of Run,
+// since decontamination cycles are explicitly designed to exceed the
+// sensor's normal 10% duty cycle for a bounded, supervised period.
+type Decontaminator struct {
+	dev  *Dev
+	opts *DecontaminatorOpts
+}
+
+// NewDecontaminator builds a Decontaminator for dev. opts may be nil to
+// accept the defaults, but Duration must still be set by the caller via
+// opts before calling Run.
+func NewDecontaminator(dev *Dev, opts *DecontaminatorOpts) *Decontaminator {
+	if opts == nil {
+		opts = DefaultDecontaminatorOpts()
+	}
+	if opts.CoolDownCeiling == 0 {
+		opts.CoolDownCeiling = 110
+	}
+	if opts.CoolDownPeriod == 0 {
+		opts.CoolDownPeriod = 10 * time.Second
+	}
+	return &Decontaminator{dev: dev, opts: opts}
+}
+
+func (dc *Decontaminator) emit(ev DecontaminationEvent) {
+	if dc.opts.OnEvent != nil {
+		dc.opts.OnEvent(ev)
+	}
+}
+
+// Run alternates HeaterHighLong bursts with sensing until the heater has
+// accumulated Duration of on-time, backing off whenever the temperature
+// exceeds CoolDownCeiling. Cool-down sleeps don't count toward Duration, so
+// a run that backs off often takes longer in wall-clock time than Duration
+// without shorting the actual decontamination on-time. Run returns early,
+// with an Aborted event and a non-nil error, if the heater or a sense call
+// fails.
+//
+// It bypasses the Dev's duty-cycle governor for its own activations only
+// (see Dev.DutyCycleUsed); it does not disable duty-cycle enforcement for
+// any other caller sharing the same Dev.
+func (dc *Decontaminator) Run() error {
+	start := time.Now()
+	var onTime time.Duration
+	for onTime < dc.opts.Duration {
+		burstStart := time.Now()
+		e, err := dc.dev.activateHeaterOverride(HeaterHighLong)
+		onTime += time.Since(burstStart)
+		if err != nil {
+			dc.emit(DecontaminationEvent{Kind: Aborted, Elapsed: time.Since(start), Err: err})
+			return err
+		}
+		dc.emit(DecontaminationEvent{Kind: CycleStarted, Env: e, Elapsed: time.Since(start)})
+
+		for e.Temperature.Celsius() > dc.opts.CoolDownCeiling {
+			dc.emit(DecontaminationEvent{Kind: CoolDown, Env: e, Elapsed: time.Since(start)})
+			time.Sleep(dc.opts.CoolDownPeriod)
+			if err := dc.dev.Sense(&e); err != nil {
+				dc.emit(DecontaminationEvent{Kind: Aborted, Elapsed: time.Since(start), Err: err})
+				return err
+			}
+		}
+	}
+
+	dc.emit(DecontaminationEvent{Kind: Completed, Elapsed: time.Since(start)})
+	return nil
+}
@@ -0,0 +1,172 @@
+package sht4x
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// Magnus-Tetens coefficients, valid over typical ambient ranges.
+const (
+	magnusB = 17.62
+	magnusC = 243.12 // °C
+
+	// molarMassWater is the molar mass of water vapor, in g/mol.
+	molarMassWater = 18.01528
+	// gasConstant is the universal gas constant, in J/(mol·K).
+	gasConstant = 8.31446
+)
+
+// Derived holds environmental quantities computed from a single T/RH
+// reading, in addition to the raw physic.Env values.
+type Derived struct {
+	Env physic.Env
+
+	// DewPoint is the temperature at which the air becomes saturated, in °C.
+	DewPoint float64
+	// AbsoluteHumidity is the mass of water vapor per volume of air, in g/m³.
+	AbsoluteHumidity float64
+	// SaturationVaporPressure is the vapor pressure at saturation, in Pa.
+	SaturationVaporPressure float64
+	// VaporPressure is the actual (partial) water-vapor pressure, in Pa.
+	VaporPressure float64
+}
+
+// SenseDerived is equivalent to SenseDerivedCtx with context.Background().
+func (d *Dev) SenseDerived(r *Derived) error {
+	return d.SenseDerivedCtx(context.Background(), r)
+}
+
+// SenseDerivedCtx is SenseDerived, but aborts an in-flight measurement wait
+// promptly if ctx is canceled.
+func (d *Dev) SenseDerivedCtx(ctx context.Context, r *Derived) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return d.wrap(errors.New("already sensing continuously"))
+	}
+
+	return d.senseDerivedCtx(ctx, r)
+}
+
+// SenseContinuousDerived is equivalent to SenseContinuousDerivedCtx with
+// context.Background().
+func (d *Dev) SenseContinuousDerived(interval time.Duration) (<-chan Derived, error) {
+	return d.SenseContinuousDerivedCtx(context.Background(), interval)
+}
+
+// SenseContinuousDerivedCtx is SenseContinuousDerived, but also terminates
+// the continuous sensing goroutine when ctx is done, in addition to Halt().
+// ctx.Err() is reported on the Errors() channel before the measurement
+// channel is closed.
+func (d *Dev) SenseContinuousDerivedCtx(ctx context.Context, interval time.Duration) (<-chan Derived, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+		d.wg.Wait()
+	}
+
+	sensing := make(chan Derived)
+	d.stop = make(chan struct{})
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuousDerived(ctx, interval, sensing, d.stop)
+	}()
+	return sensing, nil
+}
+
+func (d *Dev) senseDerived(r *Derived) error {
+	return d.senseDerivedCtx(context.Background(), r)
+}
+
+func (d *Dev) senseDerivedCtx(ctx context.Context, r *Derived) error {
+	temp, rh, err := d.measureCtx(ctx)
+	if err != nil {
+		return err
+	}
+	quantizeEnv(&r.Env, temp, rh)
+	computeDerived(r, temp, rh)
+	return nil
+}
+
+func (d *Dev) sensingContinuousDerived(ctx context.Context, interval time.Duration, sensing chan<- Derived, stop <-chan struct{}) {
+	if interval < d.measDelay {
+		interval = d.measDelay
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	d.logger.Info("continuous derived sensing started", "interval", interval)
+	defer d.logger.Info("continuous derived sensing stopped")
+
+	for {
+		r := Derived{}
+		d.mu.Lock()
+		err := d.senseDerivedCtx(ctx, &r)
+		d.mu.Unlock()
+		if err != nil {
+			d.logger.Error("sensingContinuousDerived", "error", err)
+			d.reportErr(err)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				d.reportErr(ctx.Err())
+				return
+			case <-t.C:
+				continue
+			}
+		}
+
+		select {
+		case sensing <- r:
+		case <-stop:
+			return
+		case <-ctx.Done():
+			d.reportErr(ctx.Err())
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			d.reportErr(ctx.Err())
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// minRHForDewPoint floors the RH fed into the dew-point log term. Bone-dry
+// air (rh == 0, which readTemperatureRH's clamp makes reachable, not just
+// theoretical) would otherwise take math.Log(0) to -Inf and propagate NaN
+// through DewPoint.
+const minRHForDewPoint = 1e-6
+
+// computeDerived fills in the derived quantities from the raw, unquantized
+// temperature (°C) and relative humidity (%) floats — the same values
+// already passed to quantizeEnv for r.Env — rather than reading them back
+// out of r.Env, so the math isn't subject to physic.Env's fixed-point
+// rounding.
+func computeDerived(r *Derived, t, rh float64) {
+	rhForLog := rh
+	if rhForLog < minRHForDewPoint {
+		rhForLog = minRHForDewPoint
+	}
+	gamma := math.Log(rhForLog/100) + (magnusB*t)/(magnusC+t)
+	r.DewPoint = magnusC * gamma / (magnusB - gamma)
+
+	r.SaturationVaporPressure = 611.2 * math.Exp(17.62*t/(243.12+t))
+	r.VaporPressure = r.SaturationVaporPressure * rh / 100
+	r.AbsoluteHumidity = (r.VaporPressure * molarMassWater) / (gasConstant * (t + 273.15))
+}
@@ -0,0 +1,59 @@
+package sht4x
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeDerived(t *testing.T) {
+	tests := []struct {
+		name                                                              string
+		temp, rh                                                          float64
+		wantDewPoint, wantSatVaporPressure, wantVaporPressure, wantAbsHum float64
+	}{
+		{"25C_50rh", 25, 50, 13.851583599891661, 3160.0569164883336, 1580.0284582441666, 11.482516117305213},
+		{"20C_80rh", 20, 80, 16.442430104640028, 2332.5960220978072, 1866.0768176782458, 13.792614161496862},
+		{"0C_100rh", 0, 100, 0.0, 611.2, 611.2, 4.848295801330305},
+		{"35C_10rh", 35, 10, -1.1698866795811145, 5612.841719421078, 561.2841719421078, 3.9466407112943496},
+	}
+
+	const tolerance = 1e-6
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Derived
+			computeDerived(&r, tt.temp, tt.rh)
+
+			if diff := r.DewPoint - tt.wantDewPoint; diff < -tolerance || diff > tolerance {
+				t.Errorf("DewPoint = %v, want %v", r.DewPoint, tt.wantDewPoint)
+			}
+			if diff := r.SaturationVaporPressure - tt.wantSatVaporPressure; diff < -tolerance || diff > tolerance {
+				t.Errorf("SaturationVaporPressure = %v, want %v", r.SaturationVaporPressure, tt.wantSatVaporPressure)
+			}
+			if diff := r.VaporPressure - tt.wantVaporPressure; diff < -tolerance || diff > tolerance {
+				t.Errorf("VaporPressure = %v, want %v", r.VaporPressure, tt.wantVaporPressure)
+			}
+			if diff := r.AbsoluteHumidity - tt.wantAbsHum; diff < -tolerance || diff > tolerance {
+				t.Errorf("AbsoluteHumidity = %v, want %v", r.AbsoluteHumidity, tt.wantAbsHum)
+			}
+		})
+	}
+}
+
+// TestComputeDerivedDryAir covers rh == 0, which readTemperatureRH's clamp
+// makes a reachable reading (not just a theoretical edge case), and which
+// used to take the dew-point math's log term to -Inf and poison DewPoint
+// with NaN.
+func TestComputeDerivedDryAir(t *testing.T) {
+	var r Derived
+	computeDerived(&r, 25, 0)
+
+	if math.IsNaN(r.DewPoint) || math.IsInf(r.DewPoint, 0) {
+		t.Errorf("DewPoint = %v, want a finite value", r.DewPoint)
+	}
+	if r.VaporPressure != 0 {
+		t.Errorf("VaporPressure = %v, want 0", r.VaporPressure)
+	}
+	if r.AbsoluteHumidity != 0 {
+		t.Errorf("AbsoluteHumidity = %v, want 0", r.AbsoluteHumidity)
+	}
+}
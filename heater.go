@@ -0,0 +1,186 @@
+package sht4x
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// dutyCycleEntry records a single heater activation for the purposes of
+// duty-cycle accounting.
+type dutyCycleEntry struct {
+	start time.Time
+	dur   time.Duration
+}
+
+// ErrDutyCycleExceeded is returned by ActivateHeater when the requested
+// activation would push the heater above its configured duty cycle within
+// the configured window. Use Opts.OverrideDutyCycle (or Dev.OverrideDutyCycle)
+// to bypass this check. Decontaminator bypasses it for its own activations
+// without either setting.
+type ErrDutyCycleExceeded struct {
+	Window    time.Duration
+	Limit     float64
+	Used      time.Duration
+	Requested time.Duration
+}
+
+func (e *ErrDutyCycleExceeded) Error() string {
+	return fmt.Sprintf("sht4x: heater duty cycle exceeded: %s used of %s window (%.0f%% limit), requested %s more",
+		e.Used, e.Window, e.Limit*100, e.Requested)
+}
+
+// dutyCycleUsed prunes entries outside the window and returns the heater
+// on-time recorded within it. d.mu must be held by the caller.
+func (d *Dev) dutyCycleUsed(now time.Time) time.Duration {
+	d.pruneDutyCycle(now)
+
+	var used time.Duration
+	for _, e := range d.dutyCycleLog {
+		used += e.dur
+	}
+	return used
+}
+
+// DutyCycleUsed returns how much heater on-time has been recorded within
+// the most recent Opts.DutyCycleWindow, across every caller of
+// ActivateHeater on this Dev. Callers that need their own tighter duty-cycle
+// cap, like HeaterController, should read this rather than keeping their
+// own shadow ledger, since ActivateHeater records every activation here
+// regardless of caller.
+func (d *Dev) DutyCycleUsed() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dutyCycleUsed(time.Now())
+}
+
+// checkDutyCycle reports whether adding a requested on-time would exceed
+// the configured limit. d.mu must be held by the caller.
+func (d *Dev) checkDutyCycle(now time.Time, requested time.Duration) error {
+	used := d.dutyCycleUsed(now)
+	if used+requested > time.Duration(float64(d.dutyCycleWindow)*d.dutyCycleLimit) {
+		return &ErrDutyCycleExceeded{
+			Window:    d.dutyCycleWindow,
+			Limit:     d.dutyCycleLimit,
+			Used:      used,
+			Requested: requested,
+		}
+	}
+	return nil
+}
+
+// pruneDutyCycle drops entries that have aged out of the window. d.mu must
+// be held by the caller.
+func (d *Dev) pruneDutyCycle(now time.Time) {
+	cutoff := now.Add(-d.dutyCycleWindow)
+	i := 0
+	for ; i < len(d.dutyCycleLog); i++ {
+		if d.dutyCycleLog[i].start.After(cutoff) {
+			break
+		}
+	}
+	d.dutyCycleLog = d.dutyCycleLog[i:]
+}
+
+// recordDutyCycle appends a heater activation to the duty-cycle log. d.mu
+// must be held by the caller.
+func (d *Dev) recordDutyCycle(now time.Time, dur time.Duration) {
+	d.dutyCycleLog = append(d.dutyCycleLog, dutyCycleEntry{start: now, dur: dur})
+}
+
+// ActivateHeater is equivalent to ActivateHeaterCtx with
+// context.Background().
+func (d *Dev) ActivateHeater(mode int) (physic.Env, error) {
+	return d.ActivateHeaterCtx(context.Background(), mode)
+}
+
+// ActivateHeaterCtx is ActivateHeater, but aborts the heater-on wait
+// promptly if ctx is canceled. Not intended to be used at greater than a
+// 10% duty cycle for the life of the sensor; see Opts.DutyCycleWindow and
+// Opts.DutyCycleLimit, which this method enforces by returning
+// *ErrDutyCycleExceeded.
+func (d *Dev) ActivateHeaterCtx(ctx context.Context, mode int) (physic.Env, error) {
+	d.mu.Lock()
+	override := d.OverrideDutyCycle
+	d.mu.Unlock()
+	return d.activateHeaterCtx(ctx, mode, override)
+}
+
+// activateHeaterOverride is ActivateHeater with the duty-cycle governor
+// unconditionally bypassed for this one activation, without touching the
+// shared OverrideDutyCycle field (which would affect every other caller of
+// this Dev for as long as it's set). Used by Decontaminator.
+func (d *Dev) activateHeaterOverride(mode int) (physic.Env, error) {
+	return d.activateHeaterCtx(context.Background(), mode, true)
+}
+
+// activateHeaterCtx does the actual work of ActivateHeaterCtx. override
+// bypasses the duty-cycle governor for this call only.
+func (d *Dev) activateHeaterCtx(ctx context.Context, mode int, override bool) (physic.Env, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	interval := 110 * time.Millisecond
+	long := 1100 * time.Millisecond
+
+	var cmd byte
+	var e physic.Env
+	switch mode {
+	case HeaterLow:
+		cmd = commandHeaterLow100ms
+	case HeaterLowLong:
+		cmd = commandHeaterLow1s
+		interval = long
+	case HeaterMedium:
+		cmd = commandHeaterMedium100ms
+	case HeaterMediumLong:
+		cmd = commandHeaterMedium1s
+		interval = long
+	case HeaterHigh:
+		cmd = commandHeaterHigh100ms
+	case HeaterHighLong:
+		cmd = commandHeaterHigh1s
+		interval = long
+	default:
+		return e, errors.New("sht4x: invalid heater mode")
+	}
+
+	now := time.Now()
+	if !override {
+		if err := d.checkDutyCycle(now, interval); err != nil {
+			d.logger.Warn("heater duty cycle exceeded", "mode", mode, "error", err)
+			return e, err
+		}
+	}
+
+	d.logger.Info("heater mode entered", "mode", mode, "duration", interval)
+	defer d.logger.Info("heater mode exited", "mode", mode)
+
+	if err := d.c.Tx([]byte{cmd}, nil); err != nil {
+		d.logger.Error("i2c transaction failed", "error", err)
+		return e, err
+	}
+	d.recordDutyCycle(now, interval)
+
+	if err := sleepCtx(ctx, interval); err != nil {
+		return e, err
+	}
+
+	err := d.parseTemperature(&e)
+	return e, err
+}
+
+// heaterModeDuration returns how long mode keeps the heater on for, mirroring
+// the interval selection in ActivateHeater. It lets callers like
+// HeaterController plan ahead without actually activating the heater.
+func heaterModeDuration(mode int) time.Duration {
+	switch mode {
+	case HeaterLowLong, HeaterMediumLong, HeaterHighLong:
+		return 1100 * time.Millisecond
+	default:
+		return 110 * time.Millisecond
+	}
+}
@@ -0,0 +1,61 @@
+package sht4x
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestDev() *Dev {
+	return &Dev{
+		logger:          nopLogger{},
+		dutyCycleWindow: 10 * time.Minute,
+		dutyCycleLimit:  0.1,
+	}
+}
+
+func TestCheckDutyCycle(t *testing.T) {
+	d := newTestDev()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Budget is 10% of 10 minutes == 1 minute.
+	d.recordDutyCycle(now, 50*time.Second)
+
+	if err := d.checkDutyCycle(now, 5*time.Second); err != nil {
+		t.Errorf("checkDutyCycle(5s) with 50s used = %v, want nil", err)
+	}
+	if err := d.checkDutyCycle(now, 15*time.Second); err == nil {
+		t.Errorf("checkDutyCycle(15s) with 50s used = nil, want *ErrDutyCycleExceeded")
+	} else if _, ok := err.(*ErrDutyCycleExceeded); !ok {
+		t.Errorf("checkDutyCycle error type = %T, want *ErrDutyCycleExceeded", err)
+	}
+}
+
+func TestPruneDutyCycle(t *testing.T) {
+	d := newTestDev()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d.recordDutyCycle(base, 30*time.Second)
+	d.recordDutyCycle(base.Add(5*time.Minute), 10*time.Second)
+
+	// 11 minutes later, the first entry has aged out of the 10-minute window
+	// but the second has not.
+	used := d.dutyCycleUsed(base.Add(11 * time.Minute))
+	if want := 10 * time.Second; used != want {
+		t.Errorf("dutyCycleUsed after rollover = %v, want %v", used, want)
+	}
+	if len(d.dutyCycleLog) != 1 {
+		t.Errorf("len(dutyCycleLog) after prune = %d, want 1", len(d.dutyCycleLog))
+	}
+}
+
+func TestDutyCycleUsedAccumulates(t *testing.T) {
+	d := newTestDev()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d.recordDutyCycle(now, 10*time.Second)
+	d.recordDutyCycle(now.Add(time.Second), 20*time.Second)
+
+	if used := d.dutyCycleUsed(now.Add(2 * time.Second)); used != 30*time.Second {
+		t.Errorf("dutyCycleUsed = %v, want 30s", used)
+	}
+}
@@ -0,0 +1,19 @@
+package sht4x
+
+// Logger is satisfied by *slog.Logger out of the box. Opts.Logger may be left
+// nil, in which case all logging is silently discarded.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// nopLogger discards everything. It's the default used when Opts.Logger is
+// nil so call sites never have to check for a nil Logger.
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
@@ -1,6 +1,7 @@
 package sht4x
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -16,6 +17,24 @@ type Opts struct {
 	// Address is the I2C address of the sensor
 	I2cAddress uint16
 	Name       string
+
+	// Logger receives structured events (transient I²C errors, CRC failures,
+	// heater mode changes, continuous-sensing lifecycle, reset). It may be
+	// left nil, in which case logging is a no-op. Satisfied by *slog.Logger.
+	Logger Logger
+
+	// DutyCycleWindow is the moving window over which heater on-time is
+	// tracked. Defaults to 10 minutes.
+	DutyCycleWindow time.Duration
+	// DutyCycleLimit is the maximum fraction of DutyCycleWindow the heater
+	// may be on for, per the datasheet's ≤10% duty cycle guidance. Defaults
+	// to 0.1.
+	DutyCycleLimit float64
+	// OverrideDutyCycle disables duty-cycle enforcement in ActivateHeater,
+	// for callers that knowingly exceed the normal duty cycle for a bounded,
+	// supervised period. Decontaminator does not need it: it bypasses the
+	// governor for its own activations only.
+	OverrideDutyCycle bool
 }
 
 func DefaultOpts() *Opts {
@@ -30,10 +49,27 @@ func New(b i2c.Bus, opts *Opts) (*Dev, error) {
 	if opts == nil {
 		opts = DefaultOpts()
 	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	dutyCycleWindow := opts.DutyCycleWindow
+	if dutyCycleWindow == 0 {
+		dutyCycleWindow = 10 * time.Minute
+	}
+	dutyCycleLimit := opts.DutyCycleLimit
+	if dutyCycleLimit == 0 {
+		dutyCycleLimit = 0.1
+	}
 	d := &Dev{
-		c:         i2c.Dev{Bus: b, Addr: opts.I2cAddress},
-		Name:      opts.Name,
-		measDelay: 10 * time.Millisecond,
+		c:                 i2c.Dev{Bus: b, Addr: opts.I2cAddress},
+		Name:              opts.Name,
+		measDelay:         10 * time.Millisecond,
+		logger:            logger,
+		errs:              make(chan error, 16),
+		dutyCycleWindow:   dutyCycleWindow,
+		dutyCycleLimit:    dutyCycleLimit,
+		OverrideDutyCycle: opts.OverrideDutyCycle,
 	}
 
 	// Soft reset the sensor to ensure it's in a known state
@@ -55,23 +91,61 @@ type Dev struct {
 	measDelay time.Duration
 	Name      string
 	Serial    uint32
+	logger    Logger
 
 	mu   sync.Mutex
 	stop chan struct{}
 	wg   sync.WaitGroup
+
+	errs chan error
+
+	// OverrideDutyCycle disables duty-cycle enforcement in ActivateHeater.
+	// See Opts.OverrideDutyCycle.
+	OverrideDutyCycle bool
+	dutyCycleWindow   time.Duration
+	dutyCycleLimit    float64
+	dutyCycleLog      []dutyCycleEntry
+}
+
+// Errors returns a channel of transient errors (I²C bus glitches, CRC
+// failures) encountered by SenseContinuous, SenseContinuousDerived and
+// CompensationStream. It's intended for callers that want to react to bus
+// issues without tearing down the continuous-sensing goroutine; the goroutine
+// keeps running after reporting such an error here. When the goroutine's ctx
+// is done, ctx.Err() is reported here and the goroutine then exits.
+//
+// The channel is buffered and errors are dropped rather than blocking the
+// sensing loop if the caller isn't reading from it.
+func (d *Dev) Errors() <-chan error {
+	return d.errs
 }
 
+func (d *Dev) reportErr(err error) {
+	select {
+	case d.errs <- err:
+	default:
+	}
+}
+
+// Sense is equivalent to SenseCtx with context.Background().
 func (d *Dev) Sense(e *physic.Env) error {
+	return d.SenseCtx(context.Background(), e)
+}
+
+// SenseCtx is Sense, but aborts an in-flight measurement wait promptly if
+// ctx is canceled.
+func (d *Dev) SenseCtx(ctx context.Context, e *physic.Env) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	if d.stop != nil {
 		return d.wrap(errors.New("already sensing continuously"))
 	}
 
-	return d.sense(e)
+	return d.senseCtx(ctx, e)
 }
 
-// SenseContinuous returns measurements as °C on a continuous basis.
+// SenseContinuous is equivalent to SenseContinuousCtx with
+// context.Background().
 //
 // The application must call Halt() to stop the sensing when done to stop the
 // sensor and close the channel.
@@ -79,6 +153,14 @@ func (d *Dev) Sense(e *physic.Env) error {
 // It's the responsibility of the caller to retrieve the values from the
 // channel as fast as possible, otherwise the interval may not be respected.
 func (d *Dev) SenseContinuous(interval time.Duration) (<-chan physic.Env, error) {
+	return d.SenseContinuousCtx(context.Background(), interval)
+}
+
+// SenseContinuousCtx is SenseContinuous, but also terminates the continuous
+// sensing goroutine when ctx is done, in addition to Halt(). ctx.Err() is
+// reported on the Errors() channel before the measurement channel is
+// closed.
+func (d *Dev) SenseContinuousCtx(ctx context.Context, interval time.Duration) (<-chan physic.Env, error) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	if d.stop != nil {
@@ -94,7 +176,7 @@ func (d *Dev) SenseContinuous(interval time.Duration) (<-chan physic.Env, error)
 	go func() {
 		defer d.wg.Done()
 		defer close(sensing)
-		d.sensingContinuous(interval, sensing, d.stop)
+		d.sensingContinuous(ctx, interval, sensing, d.stop)
 	}()
 	return sensing, nil
 }
@@ -127,33 +209,66 @@ func (d *Dev) Halt() error {
 }
 
 func (d *Dev) sense(e *physic.Env) error {
+	return d.senseCtx(context.Background(), e)
+}
+
+func (d *Dev) senseCtx(ctx context.Context, e *physic.Env) error {
+	temp, rh, err := d.measureCtx(ctx)
+	if err != nil {
+		return err
+	}
+	quantizeEnv(e, temp, rh)
+	return nil
+}
+
+// measureCtx triggers a high-precision measurement and returns the parsed
+// temperature (°C) and relative humidity (%) as unquantized floats.
+func (d *Dev) measureCtx(ctx context.Context) (temp, rh float64, err error) {
 	// Measure T & RH with high precision (high repeatability)
 	if err := d.c.Tx([]byte{commandMeasureHighPrecision}, nil); err != nil {
-		return err
+		d.logger.Error("i2c transaction failed", "error", err)
+		return 0, 0, err
+	}
+	if err := sleepCtx(ctx, 10*time.Millisecond); err != nil {
+		return 0, 0, err
 	}
-	time.Sleep(10 * time.Millisecond)
 
-	return d.parseTemperature(e)
+	return d.readTemperatureRH()
 }
 
 func (d *Dev) parseTemperature(e *physic.Env) error {
+	temp, rh, err := d.readTemperatureRH()
+	if err != nil {
+		return err
+	}
+	quantizeEnv(e, temp, rh)
+	return nil
+}
+
+// readTemperatureRH reads and CRC-checks a pending measurement result,
+// returning the parsed temperature (°C) and relative humidity (%) as
+// unquantized floats, before they're rounded off into physic units.
+func (d *Dev) readTemperatureRH() (temp, rh float64, err error) {
 	var data [6]byte
 	if err := d.c.Tx(nil, data[:]); err != nil {
-		return err
+		d.logger.Error("i2c transaction failed", "error", err)
+		return 0, 0, err
 	}
 
 	tTicks := readUint(data[0], data[1])
 	if err := verifyChecksum(data[:3]); err != nil {
-		return err
+		d.logger.Warn("crc failure", "field", "temperature", "error", err)
+		return 0, 0, err
 	}
 	rhTicks := readUint(data[3], data[4])
 	if err := verifyChecksum(data[3:]); err != nil {
-		return err
+		d.logger.Warn("crc failure", "field", "humidity", "error", err)
+		return 0, 0, err
 	}
 
 	// Convert ticks to physical values
-	temp := (-45 + (175 * float64(tTicks) / 65535))
-	rh := (-6 + (125 * float64(rhTicks) / 65535))
+	temp = -45 + (175 * float64(tTicks) / 65535)
+	rh = -6 + (125 * float64(rhTicks) / 65535)
 
 	// Datasheet page 13
 	if rh < 0 {
@@ -162,13 +277,17 @@ func (d *Dev) parseTemperature(e *physic.Env) error {
 		rh = 100
 	}
 
+	return temp, rh, nil
+}
+
+// quantizeEnv rounds a parsed temperature (°C) and relative humidity (%)
+// into physic.Env's fixed-point units.
+func quantizeEnv(e *physic.Env, temp, rh float64) {
 	e.Temperature = physic.Temperature(temp*1000)*physic.MilliCelsius + physic.ZeroCelsius
 	e.Humidity = physic.RelativeHumidity(rh*10000) * physic.MicroRH
-
-	return nil
 }
 
-func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- physic.Env, stop <-chan struct{}) {
+func (d *Dev) sensingContinuous(ctx context.Context, interval time.Duration, sensing chan<- physic.Env, stop <-chan struct{}) {
 	// Ensure the interval is at least the minimum measurement delay.
 	if interval < d.measDelay {
 		interval = d.measDelay
@@ -176,25 +295,46 @@ func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- physic.En
 	t := time.NewTicker(interval)
 	defer t.Stop()
 
+	d.logger.Info("continuous sensing started", "interval", interval)
+	defer d.logger.Info("continuous sensing stopped")
+
 	var err error
 	for {
 		// Do one initial sensing right away.
 		e := physic.Env{}
 		d.mu.Lock()
-		err = d.sense(&e)
+		err = d.senseCtx(ctx, &e)
 		d.mu.Unlock()
 		if err != nil {
-			fmt.Printf("sensingContinuous: %s\n", err) // FIXME
-			return
+			d.logger.Error("sensingContinuous", "error", err)
+			d.reportErr(err)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				d.reportErr(ctx.Err())
+				return
+			case <-t.C:
+				continue
+			}
 		}
 		select {
 		case sensing <- e:
 		case <-stop:
 			return
+		case <-ctx.Done():
+			d.reportErr(ctx.Err())
+			return
 		}
 		select {
 		case <-stop:
 			return
+		case <-ctx.Done():
+			d.reportErr(ctx.Err())
+			return
 		case <-t.C:
 		}
 	}
@@ -220,50 +360,22 @@ func (d *Dev) GetSerial() (uint32, error) {
 	return serial, nil
 }
 
-// ActivateHeater activates the heater for a specified duration. Not intended to be used
-// at greater than a 10% duty cycle for the life of the sensor.
-func (d *Dev) ActivateHeater(mode int) (physic.Env, error) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	interval := 110 * time.Millisecond
-	long := 1100 * time.Millisecond
-
-	var cmd byte
-	var e physic.Env
-	switch mode {
-	case HeaterLow:
-		cmd = commandHeaterLow100ms
-	case HeaterLowLong:
-		cmd = commandHeaterLow1s
-		interval = long
-	case HeaterMedium:
-		cmd = commandHeaterMedium100ms
-	case HeaterMediumLong:
-		cmd = commandHeaterMedium1s
-		interval = long
-	case HeaterHigh:
-		cmd = commandHeaterHigh100ms
-	case HeaterHighLong:
-		cmd = commandHeaterHigh1s
-		interval = long
-	default:
-		return e, errors.New("sht4x: invalid heater mode")
-	}
-
-	if err := d.c.Tx([]byte{cmd}, nil); err != nil {
-		return e, err
-	}
-
-	time.Sleep(interval)
-
-	err := d.parseTemperature(&e)
-	return e, err
+// Reset is equivalent to ResetCtx with context.Background().
+func (d *Dev) Reset() error {
+	return d.ResetCtx(context.Background())
 }
 
-func (d *Dev) Reset() error {
+// ResetCtx is Reset, but aborts the post-reset wait promptly if ctx is
+// canceled.
+func (d *Dev) ResetCtx(ctx context.Context) error {
+	d.logger.Debug("resetting sensor")
 	err := d.c.Tx([]byte{commandSoftReset}, nil)
-	time.Sleep(1 * time.Millisecond)
+	if serr := sleepCtx(ctx, 1*time.Millisecond); serr != nil {
+		return serr
+	}
+	if err != nil {
+		d.logger.Error("reset failed", "error", err)
+	}
 	return err
 }
 
@@ -271,6 +383,19 @@ func (d *Dev) wrap(err error) error {
 	return fmt.Errorf("%s: %v", strings.ToLower(d.Name), err)
 }
 
+// sleepCtx sleeps for dur, or returns ctx.Err() early if ctx is canceled
+// first.
+func sleepCtx(ctx context.Context, dur time.Duration) error {
+	t := time.NewTimer(dur)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
 func readUint(msb, lsb byte) uint16 {
 	return uint16(msb)<<8 | uint16(lsb)
 }